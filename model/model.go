@@ -0,0 +1,179 @@
+// Package model holds the types shared between the bot's rule engine and
+// its Remote implementations (Slack, Discord, etc.). A Remote only ever
+// sees these types - never the other transports it's built alongside.
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Bot holds the runtime configuration and state a Remote needs to read
+// and send messages on behalf of the bot it's attached to. It's
+// unmarshaled directly from bot.yml, so every configurable field below
+// carries the yaml key a user sets to reach it; Log/ID/CLI/Channels are
+// runtime-only and excluded.
+type Bot struct {
+	Log *logrus.Logger `yaml:"-"`
+
+	ID       string   `yaml:"-"`
+	CLI      bool     `yaml:"-"`
+	Channels Channels `yaml:"-"`
+
+	InteractiveComponents bool `yaml:"interactive_components"`
+
+	SlackToken string `yaml:"slack_token"`
+
+	// SlackAppToken is the Slack app-level token (xapp-...) that turns on
+	// Socket Mode instead of RTM/the Events API. The Slack app needs the
+	// connections:write scope for apps.connections.open to succeed.
+	SlackAppToken          string `yaml:"slack_app_token"`
+	SlackVerificationToken string `yaml:"slack_verification_token"`
+	SlackWorkspaceToken    string `yaml:"slack_workspace_token"`
+
+	// SlackErrorChannel, when set, is the channel error-level (and above)
+	// log entries are also published to.
+	SlackErrorChannel string `yaml:"error_channel"`
+
+	SlackEventsCallbackPath       string `yaml:"slack_events_callback_path"`
+	SlackInteractionsCallbackPath string `yaml:"slack_interactions_callback_path"`
+	SlackInteractionsListenAddr   string `yaml:"slack_interactions_listen_addr"`
+	SlackInteractionsTLSCert      string `yaml:"slack_interactions_tls_cert"`
+	SlackInteractionsTLSKey       string `yaml:"slack_interactions_tls_key"`
+}
+
+// Rule describes a single trigger/response pair a Remote checks incoming
+// messages against.
+type Rule struct {
+	Name string
+
+	RemoveReaction string
+	Reaction       string
+
+	// Modal, when set (Modal.Title is non-empty), turns this rule into a
+	// multi-field prompt instead of a plain response: the Remote opens a
+	// modal and feeds the user's answers back in as a follow-up Message.
+	Modal Modal
+}
+
+// Message is the unit of communication passed between a Remote and the
+// rule engine, in both directions.
+type Message struct {
+	ID string
+
+	Type   MsgType
+	Action MsgAction
+
+	ChannelID string
+	Timestamp string
+
+	// EditOfTimestamp is the Timestamp of a message this Message edits or
+	// deletes, set on an outbound Message when Action is MsgActionUpdate
+	// or MsgActionDelete, and on an inbound Message synthesized for a
+	// message_changed/message_deleted event.
+	EditOfTimestamp string
+
+	TriggerID string
+
+	Input  string
+	Output string
+
+	// Values holds a modal submission's answers, keyed by the ModalInput
+	// Name that collected each one. Set on the follow-up Message a modal
+	// produces instead of appending them to Input, since answers can
+	// contain the spaces/'='s a hand-rolled delimited string can't
+	// distinguish from a field boundary.
+	Values map[string]string
+
+	Blocks      string
+	Attachments []Attachment
+
+	StartTime string
+	EndTime   string
+}
+
+// NewMessage returns a Message stamped with a fresh ID and start time,
+// ready for a Remote to populate.
+func NewMessage() Message {
+	return Message{
+		ID:        uuid.New().String(),
+		StartTime: MessageTimestamp(),
+	}
+}
+
+// MessageTimestamp returns the current time as a whole-second Unix
+// timestamp string, the format Message.StartTime/EndTime use.
+// Message.Timestamp instead holds the message ts exactly as Slack sent it.
+func MessageTimestamp() string {
+	return fmt.Sprintf("%d", time.Now().Unix())
+}
+
+// MsgType identifies where a Message came from or is headed.
+type MsgType string
+
+// Supported message types.
+const (
+	MsgTypeDirect         MsgType = "direct_message"
+	MsgTypeChannel        MsgType = "channel"
+	MsgTypePrivateChannel MsgType = "private_channel"
+)
+
+// MsgAction distinguishes an edit or delete of a previously sent message
+// from posting a new one.
+type MsgAction string
+
+// Supported message actions. The zero value means "post a new message".
+const (
+	MsgActionUpdate MsgAction = "update"
+	MsgActionDelete MsgAction = "delete"
+)
+
+// Channels is the set of channels a bot belongs to.
+type Channels []string
+
+// BotUser identifies the bot's own account on a Remote.
+type BotUser struct {
+	ID   string
+	Name string
+}
+
+// Attachment is a rule's compact, declarative way to send a colored,
+// structured attachment instead of (or alongside) plain templated text.
+type Attachment struct {
+	Title  string
+	Text   string
+	Color  string
+	Status string
+
+	Fields  []AttachmentField
+	Actions []AttachmentAction
+}
+
+// AttachmentField is a single title/value pair rendered inside an
+// Attachment.
+type AttachmentField struct {
+	Title string
+	Value string
+	Short bool
+}
+
+// AttachmentAction is a button rendered inside an Attachment.
+type AttachmentAction struct {
+	Name  string
+	Value string
+}
+
+// Modal describes a rule's multi-field input prompt.
+type Modal struct {
+	Title  string
+	Inputs []ModalInput
+}
+
+// ModalInput is a single labeled field collected by a Modal.
+type ModalInput struct {
+	Name  string
+	Label string
+}
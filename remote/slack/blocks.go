@@ -0,0 +1,269 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/slack-go/slack"
+	"github.com/target/flottbot/model"
+)
+
+// sendWithBlocks posts a message rendered with Block Kit blocks and/or
+// rich attachments instead of the plain-text path in send(). It reads
+// them from message.Blocks/message.Attachments (model.Rule has no such
+// fields) and mixes either with the templated message Output, which Slack
+// renders as the notification text.
+func sendWithBlocks(api *slack.Client, message model.Message, bot *model.Bot) {
+	options := []slack.MsgOption{slack.MsgOptionText(message.Output, false)}
+
+	if len(message.Blocks) > 0 {
+		blocks, err := buildBlocks(message.Blocks)
+		if err != nil {
+			bot.Log.Errorf("Could not parse blocks for message %s: %s", message.ID, err)
+		} else {
+			options = append(options, slack.MsgOptionBlocks(blocks...))
+		}
+	}
+
+	if len(message.Attachments) > 0 {
+		attachments := make([]slack.Attachment, 0, len(message.Attachments))
+		for _, a := range message.Attachments {
+			attachments = append(attachments, buildAttachment(a))
+		}
+
+		options = append(options, slack.MsgOptionAttachments(attachments...))
+	}
+
+	_, _, err := api.PostMessage(message.ChannelID, options...)
+	if err != nil {
+		bot.Log.Errorf("Could not send message %s: %s", message.ID, err)
+	}
+}
+
+// buildBlocks turns a templated Block Kit JSON payload
+// (model.Message.Blocks; model.Rule has no Blocks field) into slack.Block
+// values suitable for slack.MsgOptionBlocks.
+func buildBlocks(raw string) ([]slack.Block, error) {
+	var payload struct {
+		Blocks []json.RawMessage `json:"blocks"`
+	}
+
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]slack.Block, 0, len(payload.Blocks))
+
+	for _, rawBlock := range payload.Blocks {
+		block, err := slack.UnmarshalBlock(rawBlock)
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// buildAttachment converts a rule's compact declarative attachment
+// (model.Attachment) into a slack.Attachment, mapping the rule's
+// status to an attachment color the way Slack's own integrations do.
+func buildAttachment(attachment model.Attachment) slack.Attachment {
+	color := attachment.Color
+	if len(color) == 0 {
+		switch attachment.Status {
+		case "success":
+			color = "good"
+		case "warning":
+			color = "warning"
+		case "error":
+			color = "danger"
+		}
+	}
+
+	fields := make([]slack.AttachmentField, 0, len(attachment.Fields))
+	for _, f := range attachment.Fields {
+		fields = append(fields, slack.AttachmentField{
+			Title: f.Title,
+			Value: f.Value,
+			Short: f.Short,
+		})
+	}
+
+	actions := make([]slack.AttachmentAction, 0, len(attachment.Actions))
+	for _, a := range attachment.Actions {
+		actions = append(actions, slack.AttachmentAction{
+			Name:  a.Name,
+			Text:  a.Name,
+			Type:  "button",
+			Value: a.Value,
+		})
+	}
+
+	return slack.Attachment{
+		Title:   attachment.Title,
+		Text:    attachment.Text,
+		Color:   color,
+		Fields:  fields,
+		Actions: actions,
+	}
+}
+
+// pendingModal is what pendingModals tracks between openModal and the
+// view_submission follow-up: the message/rule context to carry over, and
+// the Modal spec itself so the submission's fields can be read back out
+// in the order the rule declared them.
+type pendingModal struct {
+	message model.Message
+	modal   model.Modal
+}
+
+// pendingModals correlates an open modal's callback UUID back to the
+// pendingModal that triggered it, so the view_submission follow-up can
+// be turned into a model.Message carrying the same trigger context.
+// It's written from openModal and from the HTTP interactions handler and
+// the Socket Mode reader goroutine, so all access goes through
+// pendingModalsMu.
+var (
+	pendingModalsMu sync.Mutex
+	pendingModals   = map[string]pendingModal{}
+)
+
+// openModal opens a rule's input_text modal via views.open, tagging it
+// with a fresh callback UUID so the submission can be correlated later.
+func openModal(api *slack.Client, rule model.Rule, message *model.Message, bot *model.Bot) {
+	callbackID := uuid.New().String()
+
+	pendingModalsMu.Lock()
+	pendingModals[callbackID] = pendingModal{message: *message, modal: rule.Modal}
+	pendingModalsMu.Unlock()
+
+	view := buildModalView(rule.Modal, callbackID)
+
+	if _, err := api.OpenView(message.TriggerID, view); err != nil {
+		bot.Log.Errorf("Could not open modal for rule %s: %s", rule.Name, err)
+
+		pendingModalsMu.Lock()
+		delete(pendingModals, callbackID)
+		pendingModalsMu.Unlock()
+	}
+}
+
+// dispatchViewCallback handles the two view lifecycle callbacks a modal
+// opened by openModal can produce: a view_submission, which it turns into
+// a follow-up model.Message on inputMsgs, and a view_closed (the user
+// cancelled/dismissed it), which just clears the pending entry so it
+// doesn't leak. It reports whether callback was one of these two types,
+// so callers can fall through to their normal handling otherwise. Shared
+// by the HTTP interactions handler and the Socket Mode reader so a modal
+// behaves the same regardless of transport.
+func dispatchViewCallback(callback slack.InteractionCallback, inputMsgs chan<- model.Message, bot *model.Bot) bool {
+	switch callback.Type {
+	case slack.InteractionTypeViewSubmission:
+		pendingModalsMu.Lock()
+		pending, ok := pendingModals[callback.View.CallbackID]
+		if ok {
+			delete(pendingModals, callback.View.CallbackID)
+		}
+		pendingModalsMu.Unlock()
+
+		if !ok {
+			bot.Log.Warn("Received view_submission for an unknown modal")
+			return true
+		}
+
+		followUp := pending.message
+		followUp.Input = ""
+		followUp.Values = make(map[string]string, len(pending.modal.Inputs))
+
+		// Read fields back out in the order the rule's Modal.Inputs
+		// declared them - not by ranging over the response's map, whose
+		// iteration order Go randomizes - into a real map keyed by field
+		// name, since a submitted value can itself contain the
+		// spaces/'='s a delimited string can't distinguish from a field
+		// boundary.
+		for _, input := range pending.modal.Inputs {
+			blockValues, ok := callback.View.State.Values[input.Name]
+			if !ok {
+				continue
+			}
+
+			if value, ok := blockValues[input.Name]; ok {
+				followUp.Values[input.Name] = value.Value
+			}
+		}
+
+		inputMsgs <- followUp
+
+		return true
+	case slack.InteractionTypeViewClosed:
+		pendingModalsMu.Lock()
+		delete(pendingModals, callback.View.CallbackID)
+		pendingModalsMu.Unlock()
+
+		return true
+	default:
+		return false
+	}
+}
+
+// getViewSubmissionHandler wraps an existing interactions handler,
+// intercepting view callbacks for modals opened by openModal via
+// dispatchViewCallback. Any other payload (e.g. block_actions) is passed
+// through to next.
+func getViewSubmissionHandler(verificationToken string, inputMsgs chan<- model.Message, bot *model.Bot, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			next(w, r)
+			return
+		}
+
+		payload := r.FormValue("payload")
+		if len(payload) == 0 {
+			next(w, r)
+			return
+		}
+
+		var callback slack.InteractionCallback
+		if err := json.Unmarshal([]byte(payload), &callback); err != nil {
+			next(w, r)
+			return
+		}
+
+		if dispatchViewCallback(callback, inputMsgs, bot) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// buildModalView builds a Slack modal (views.open payload) from a rule's
+// input_text spec so rules can gather multi-field input from a user
+// instead of only reacting to a single message.
+func buildModalView(modal model.Modal, callbackID string) slack.ModalViewRequest {
+	blocks := make([]slack.Block, 0, len(modal.Inputs))
+
+	for _, input := range modal.Inputs {
+		blocks = append(blocks, slack.NewInputBlock(
+			input.Name,
+			slack.NewTextBlockObject(slack.PlainTextType, input.Label, false, false),
+			nil,
+			slack.NewPlainTextInputBlockElement(nil, input.Name),
+		))
+	}
+
+	return slack.ModalViewRequest{
+		Type:          slack.ViewType("modal"),
+		Title:         slack.NewTextBlockObject(slack.PlainTextType, modal.Title, false, false),
+		Submit:        slack.NewTextBlockObject(slack.PlainTextType, "Submit", false, false),
+		Close:         slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		CallbackID:    callbackID,
+		Blocks:        slack.Blocks{BlockSet: blocks},
+		NotifyOnClose: true,
+	}
+}
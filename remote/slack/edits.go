@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/target/flottbot/model"
+)
+
+// updateMessage replaces the text of a message the bot already posted,
+// identified by message.EditOfTimestamp, via chat.update. Rules that post
+// progress updates use this to edit their own prior message in place
+// instead of spamming the channel with a new one each time.
+func updateMessage(api *slack.Client, message model.Message, bot *model.Bot) {
+	_, _, _, err := api.UpdateMessage(message.ChannelID, message.EditOfTimestamp, slack.MsgOptionText(message.Output, false))
+	if err != nil {
+		bot.Log.Errorf("Could not update message %s: %s", message.EditOfTimestamp, err)
+	}
+}
+
+// deleteMessage removes a message the bot already posted, identified by
+// message.EditOfTimestamp, via chat.delete.
+func deleteMessage(api *slack.Client, message model.Message, bot *model.Bot) {
+	_, _, err := api.DeleteMessage(message.ChannelID, message.EditOfTimestamp)
+	if err != nil {
+		bot.Log.Errorf("Could not delete message %s: %s", message.EditOfTimestamp, err)
+	}
+}
+
+// newEditedMessage builds the synthetic model.Message handleMessageSubtype
+// emits for a message_changed subtype, so rules can react to corrections
+// the same way they react to new messages. Timestamp is set to the
+// original message's timestamp (not the edit event's own timestamp) so
+// rules can correlate the edit with the message it replaces.
+func newEditedMessage(channelID, timestamp, newText string) model.Message {
+	msg := model.NewMessage()
+	msg.Action = model.MsgActionUpdate
+	msg.Type = messageType(channelID)
+	msg.ChannelID = channelID
+	msg.Timestamp = timestamp
+	msg.EditOfTimestamp = timestamp
+	msg.Input = newText
+
+	return msg
+}
+
+// newDeletedMessage builds the synthetic model.Message handleMessageSubtype
+// emits for a message_deleted subtype.
+func newDeletedMessage(channelID, timestamp string) model.Message {
+	msg := model.NewMessage()
+	msg.Action = model.MsgActionDelete
+	msg.Type = messageType(channelID)
+	msg.ChannelID = channelID
+	msg.Timestamp = timestamp
+	msg.EditOfTimestamp = timestamp
+
+	return msg
+}
+
+// messageType maps a Slack channel ID's prefix to the message type rules
+// match on: direct message channel IDs start with D, private channels
+// (and legacy private groups) start with G, everything else is a public
+// channel.
+func messageType(channelID string) model.MsgType {
+	switch {
+	case strings.HasPrefix(channelID, "D"):
+		return model.MsgTypeDirect
+	case strings.HasPrefix(channelID, "G"):
+		return model.MsgTypePrivateChannel
+	default:
+		return model.MsgTypeChannel
+	}
+}
+
+// handleMessageSubtype inspects a Slack message event's subtype and, for
+// message_changed/message_deleted, emits the corresponding synthetic
+// message so rules can react to edits and deletions the same way they
+// react to new messages. It reports whether subtype was one it handles.
+// parseEventsAPIInnerEvent (which Events API and Socket Mode both funnel
+// through) calls this only for the message_changed/message_deleted
+// subtypes and ignores the result; every other message event takes its
+// normal new-message handling without reaching this function. The legacy RTM
+// reader referenced elsewhere in this package (readFromRTM) is not part
+// of this snapshot and does not call it.
+func handleMessageSubtype(subtype, channelID, timestamp, newText string, inputMsgs chan<- model.Message) bool {
+	switch subtype {
+	case "message_changed":
+		inputMsgs <- newEditedMessage(channelID, timestamp, newText)
+		return true
+	case "message_deleted":
+		inputMsgs <- newDeletedMessage(channelID, timestamp)
+		return true
+	default:
+		return false
+	}
+}
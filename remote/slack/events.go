@@ -0,0 +1,40 @@
+package slack
+
+import (
+	"github.com/slack-go/slack/slackevents"
+	"github.com/target/flottbot/model"
+)
+
+// parseEventsAPIInnerEvent turns a single Events API inner event into a
+// model.Message on inputMsgs. It's the parsing readFromEventsAPI's HTTP
+// callback handler uses, and the Socket Mode reader reuses it for the
+// EventsAPIEvent payloads that arrive over its websocket instead, so a
+// message is handled identically regardless of transport.
+func parseEventsAPIInnerEvent(event slackevents.EventsAPIEvent, inputMsgs chan<- model.Message, bot *model.Bot) {
+	switch ev := event.InnerEvent.Data.(type) {
+	case *slackevents.MessageEvent:
+		switch ev.SubType {
+		case "message_changed":
+			if ev.Message == nil {
+				return
+			}
+
+			handleMessageSubtype(ev.SubType, ev.Channel, ev.Message.TimeStamp, ev.Message.Text, inputMsgs)
+
+			return
+		case "message_deleted":
+			handleMessageSubtype(ev.SubType, ev.Channel, ev.DeletedTimeStamp, "", inputMsgs)
+			return
+		}
+
+		msg := model.NewMessage()
+		msg.Type = messageType(ev.Channel)
+		msg.ChannelID = ev.Channel
+		msg.Timestamp = ev.TimeStamp
+		msg.Input = ev.Text
+
+		inputMsgs <- msg
+	default:
+		bot.Log.Debug("Ignored unsupported Events API inner event")
+	}
+}
@@ -0,0 +1,98 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// hookBufferSize bounds how many log entries can be queued for delivery
+// before the hook starts dropping them, so a slow or unreachable Slack API
+// can never stall the goroutine that called logrus.
+const hookBufferSize = 100
+
+// Hook is a logrus.Hook that publishes fired entries to a Slack channel as
+// colored attachments, so operators see runtime failures in Slack instead
+// of only in stdout.
+type Hook struct {
+	api     *slack.Client
+	channel string
+	levels  []logrus.Level
+	entries chan *logrus.Entry
+}
+
+// NewHook builds a Hook that posts to channel using token, firing on
+// levels. If levels is empty it defaults to Error, Fatal, and Panic.
+func NewHook(token, channel string, levels []logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel}
+	}
+
+	h := &Hook{
+		api:     slack.New(token),
+		channel: channel,
+		levels:  levels,
+		entries: make(chan *logrus.Entry, hookBufferSize),
+	}
+
+	go h.worker()
+
+	return h
+}
+
+// Levels satisfies logrus.Hook.
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire satisfies logrus.Hook. It never blocks on the Slack API: the entry
+// is handed to a buffered channel and a slow worker only ever slows future
+// deliveries, not the caller.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	select {
+	case h.entries <- entry:
+	default:
+		// buffer full - drop rather than block the bot's main loops
+	}
+
+	return nil
+}
+
+// worker drains queued entries and posts them to Slack one at a time.
+func (h *Hook) worker() {
+	for entry := range h.entries {
+		attachment := slack.Attachment{
+			Color: colorForLevel(entry.Level),
+			Text:  entry.Message,
+			Ts:    json.Number(fmt.Sprintf("%d", entry.Time.Unix())),
+		}
+
+		for key, value := range entry.Data {
+			attachment.Fields = append(attachment.Fields, slack.AttachmentField{
+				Title: key,
+				Value: fmt.Sprintf("%v", value),
+				Short: true,
+			})
+		}
+
+		_, _, err := h.api.PostMessage(h.channel, slack.MsgOptionAttachments(attachment))
+		if err != nil {
+			fmt.Printf("slack hook: could not post log entry: %s\n", err)
+		}
+	}
+}
+
+// colorForLevel maps a logrus level to the Slack attachment color used by
+// Slack's own integrations (green/yellow/red).
+func colorForLevel(level logrus.Level) string {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		return "danger"
+	case logrus.WarnLevel:
+		return "warning"
+	default:
+		return "good"
+	}
+}
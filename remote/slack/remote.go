@@ -1,10 +1,13 @@
 package slack
 
 import (
+	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/nlopes/slack"
+	"github.com/slack-go/slack"
 	"github.com/target/flottbot/model"
 	"github.com/target/flottbot/remote"
 )
@@ -18,17 +21,44 @@ Implementation for the Remote interface
 // Client struct
 type Client struct {
 	Token             string
+	AppToken          string
 	VerificationToken string
 	WorkspaceToken    string
+
+	// Router lets callers share one mux.Router (and therefore one
+	// listener) between the Events API callback and the interactions
+	// callback. If nil, InteractiveComponents creates its own.
+	Router *mux.Router
+
+	interactionsMu     sync.Mutex
+	interactionsServer *http.Server
 }
 
 // validate that Client adheres to remote interface
 var _ remote.Remote = (*Client)(nil)
 
+// NewClient builds a Client from the Slack settings bot.yml loaded onto
+// bot, so callers wiring up a bot's remote don't need to know which of
+// bot's fields belong to this particular Remote.
+func NewClient(bot *model.Bot) *Client {
+	return &Client{
+		Token:             bot.SlackToken,
+		AppToken:          bot.SlackAppToken,
+		VerificationToken: bot.SlackVerificationToken,
+		WorkspaceToken:    bot.SlackWorkspaceToken,
+	}
+}
+
 // instantiate a new slack client
 func (c *Client) new() *slack.Client {
-	api := slack.New(c.Token)
-	return api
+	if len(c.AppToken) > 0 {
+		// Socket Mode's apps.connections.open call authenticates with the
+		// app-level token, which slack-go expects on the REST client
+		// itself rather than on socketmode.New.
+		return slack.New(c.Token, slack.OptionAppLevelToken(c.AppToken))
+	}
+
+	return slack.New(c.Token)
 }
 
 func (c *Client) Channels() (*model.Channels, error) {
@@ -73,6 +103,11 @@ func (c *Client) Read(inputMsgs chan<- model.Message, rules map[string]model.Rul
 	// init api client
 	api := c.new()
 
+	// publish error-level (and above) log entries to Slack, if configured
+	if len(bot.SlackErrorChannel) > 0 {
+		bot.Log.AddHook(NewHook(c.Token, bot.SlackErrorChannel, nil))
+	}
+
 	// get bot channels
 	bot.Channels = getChannels(api)
 
@@ -85,7 +120,10 @@ func (c *Client) Read(inputMsgs chan<- model.Message, rules map[string]model.Rul
 	}
 
 	// read messages
-	if len(c.VerificationToken) > 0 {
+	if len(c.AppToken) > 0 {
+		bot.ID = rat.UserID
+		readFromSocketMode(api, inputMsgs, bot)
+	} else if len(c.VerificationToken) > 0 {
 		if len(bot.SlackEventsCallbackPath) == 0 {
 			bot.Log.Error("Need to specify a callback path for the 'slack_events_callback_path' field in the bot.yml (e.g. \"/slack_events/v1/mybot-v1_events\")")
 			bot.Log.Debug("Closing events reader (will not be able to read messages)")
@@ -97,7 +135,7 @@ func (c *Client) Read(inputMsgs chan<- model.Message, rules map[string]model.Rul
 			return
 		}
 		bot.ID = rat.UserID
-		readFromEventsAPI(api, c.VerificationToken, inputMsgs, bot)
+		readFromEventsAPI(api, c.VerificationToken, c.Router, inputMsgs, bot)
 	} else if len(c.Token) > 0 {
 		bot.ID = rat.UserID
 		rtm := api.NewRTM()
@@ -127,52 +165,140 @@ func (c *Client) Send(message model.Message, bot *model.Bot) {
 	// Timestamp message
 	message.EndTime = model.MessageTimestamp()
 
+	// an edit or delete targets a message the bot already posted, rather
+	// than posting a new one, so it's dispatched before the type switch
+	// below (which only knows how to post)
+	switch message.Action {
+	case model.MsgActionUpdate:
+		updateMessage(api, message, bot)
+		return
+	case model.MsgActionDelete:
+		deleteMessage(api, message, bot)
+		return
+	}
+
 	// send message  based on type
 	switch message.Type {
 	case model.MsgTypeDirect, model.MsgTypeChannel, model.MsgTypePrivateChannel:
+		if len(message.Blocks) > 0 || len(message.Attachments) > 0 {
+			sendWithBlocks(api, message, bot)
+			return
+		}
 		send(api, message, bot)
 	default:
 		bot.Log.Warn("Received unknown  message type - no message to send")
 	}
 }
 
-var interactionsRouter *mux.Router
-
 // InteractiveComponents implementation to satisfy remote interface
 // It will serve as a way for your bot to handle advance messaging, such as message attachments.
 // When your bot is up and running, it will have an http/https endpoint to handle rules for sending attachments.
 func (c *Client) InteractiveComponents(inputMsgs chan<- model.Message, message *model.Message, rule model.Rule, bot *model.Bot) {
-	if bot.InteractiveComponents && len(c.VerificationToken) > 0 {
+	if bot.InteractiveComponents && (len(c.VerificationToken) > 0 || len(c.AppToken) > 0) {
+		// Rules with an input_text modal spec gather multi-field input
+		// from the user instead of posting a message; the follow-up
+		// view_submission payload comes back as a new model.Message via
+		// the Socket Mode reader or, over HTTP, the interactions router
+		// set up below (so an HTTP modal is only opened once it exists).
+		//
+		// Socket Mode delivers interaction payloads over the same websocket
+		// used to read events, so there's no HTTP callback to stand up.
+		if len(c.AppToken) > 0 {
+			if rule.Modal.Title != "" {
+				openModal(c.new(), rule, message, bot)
+				return
+			}
+			processInteractiveComponentRule(rule, message, bot)
+			return
+		}
+
 		if len(bot.SlackInteractionsCallbackPath) == 0 {
 			bot.Log.Error("Need to specify a callback path for the 'slack_interactions_callback_path' field in the bot.yml (e.g. \"/slack_events/v1/mybot_dev-v1_interactions\")")
 			bot.Log.Warn("Closing interactions reader (will not be able to read interactive components)")
 			return
 		}
-		if interactionsRouter == nil {
-			// create router for the Interactive Components server
-			interactionsRouter = mux.NewRouter()
+		c.interactionsMu.Lock()
+		if c.interactionsServer == nil {
+			// reuse a caller-provided router (e.g. shared with the Events
+			// API callback) or stand up our own
+			router := c.Router
+			if router == nil {
+				router = mux.NewRouter()
+			}
 
 			// interaction health check handler
-			interactionsRouter.HandleFunc("/interaction_health", getInteractiveComponentHealthHandler(bot)).Methods("GET")
+			router.HandleFunc("/interaction_health", getInteractiveComponentHealthHandler(bot)).Methods("GET")
 
-			// Rule handler and endpoint
-			ruleHandle := getInteractiveComponentRuleHandler(c.VerificationToken, inputMsgs, message, rule, bot)
+			// Rule handler and endpoint. view_submission payloads (modal
+			// follow-ups) are intercepted first; everything else falls
+			// through to the existing rule handler unchanged.
+			ruleHandle := getViewSubmissionHandler(
+				c.VerificationToken,
+				inputMsgs,
+				bot,
+				getInteractiveComponentRuleHandler(c.VerificationToken, inputMsgs, message, rule, bot),
+			)
 
 			// We use regex for interactions routing for any bot using this framework
 			// e.g. /slack_events/v1/mybot_dev-v1_interactions
 			if !isValidPath(bot.SlackInteractionsCallbackPath) {
 				bot.Log.Error("Invalid events path. Please double check your path value/syntax (e.g. \"/slack_events/v1/mybot_dev-v1_interactions\")")
 				bot.Log.Warn("Closing interaction components reader (will not be able to read interactive components)")
+				c.interactionsMu.Unlock()
 				return
 			}
-			interactionsRouter.HandleFunc(bot.SlackInteractionsCallbackPath, ruleHandle).Methods("POST")
+			router.HandleFunc(bot.SlackInteractionsCallbackPath, ruleHandle).Methods("POST")
+
+			addr := bot.SlackInteractionsListenAddr
+			if len(addr) == 0 {
+				addr = ":4000"
+			}
+
+			c.interactionsServer = &http.Server{
+				Addr:         addr,
+				Handler:      router,
+				ReadTimeout:  5 * time.Second,
+				WriteTimeout: 10 * time.Second,
+			}
 
 			// start Interactive Components server
-			go http.ListenAndServe(":4000", interactionsRouter)
-			bot.Log.Infof("Slack Interactive Components server is listening to %s", bot.SlackInteractionsCallbackPath)
+			go func() {
+				var err error
+				if len(bot.SlackInteractionsTLSCert) > 0 && len(bot.SlackInteractionsTLSKey) > 0 {
+					err = c.interactionsServer.ListenAndServeTLS(bot.SlackInteractionsTLSCert, bot.SlackInteractionsTLSKey)
+				} else {
+					err = c.interactionsServer.ListenAndServe()
+				}
+				if err != nil && err != http.ErrServerClosed {
+					bot.Log.Errorf("Slack Interactive Components server stopped: %s", err)
+				}
+			}()
+			bot.Log.Infof("Slack Interactive Components server is listening on %s at %s", addr, bot.SlackInteractionsCallbackPath)
+		}
+		c.interactionsMu.Unlock()
+
+		if rule.Modal.Title != "" {
+			openModal(c.new(), rule, message, bot)
+			return
 		}
 
 		// Process the hit rule for Interactive Components, e.g. interactive messages
 		processInteractiveComponentRule(rule, message, bot)
 	}
 }
+
+// Shutdown gracefully stops the Interactive Components server, if one was
+// started, so a bot can be torn down cleanly instead of leaking a listener.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.interactionsMu.Lock()
+	defer c.interactionsMu.Unlock()
+
+	if c.interactionsServer == nil {
+		return nil
+	}
+
+	err := c.interactionsServer.Shutdown(ctx)
+	c.interactionsServer = nil
+
+	return err
+}
@@ -0,0 +1,166 @@
+package slack
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/target/flottbot/model"
+)
+
+func TestClient_Shutdown_NoServer(t *testing.T) {
+	c := &Client{}
+
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no error shutting down a client with no server, got %s", err)
+	}
+}
+
+func TestClient_Shutdown_StopsServer(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/interaction_health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	router.HandleFunc("/interactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("POST")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not bind listener: %s", err)
+	}
+
+	c := &Client{
+		interactionsServer: &http.Server{Handler: router},
+	}
+
+	go c.interactionsServer.Serve(listener)
+
+	addr := "http://" + listener.Addr().String()
+
+	if _, err := http.Get(addr + "/interaction_health"); err != nil {
+		t.Fatalf("expected health endpoint to be reachable: %s", err)
+	}
+
+	if _, err := http.Post(addr+"/interactions", "application/x-www-form-urlencoded", nil); err != nil {
+		t.Fatalf("expected interactions endpoint to be reachable: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.Shutdown(ctx); err != nil {
+		t.Fatalf("expected graceful shutdown to succeed, got %s", err)
+	}
+
+	if _, err := http.Get(addr + "/interaction_health"); err == nil {
+		t.Fatal("expected health endpoint to be unreachable after shutdown")
+	}
+}
+
+// hasRoute reports whether router has a route registered for path.
+func hasRoute(router *mux.Router, path string) bool {
+	found := false
+
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if tmpl, err := route.GetPathTemplate(); err == nil && tmpl == path {
+			found = true
+		}
+		return nil
+	})
+
+	return found
+}
+
+func TestClient_InteractiveComponents_StartsServer(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	router := mux.NewRouter()
+	bot := &model.Bot{
+		Log:                           logger,
+		InteractiveComponents:         true,
+		SlackInteractionsCallbackPath: "/slack/interactions",
+		SlackInteractionsListenAddr:   "127.0.0.1:0",
+	}
+	c := &Client{VerificationToken: "test-verification-token", Router: router}
+	inputMsgs := make(chan model.Message, 1)
+	rule := model.Rule{Name: "test-rule"}
+	message := &model.Message{}
+
+	c.InteractiveComponents(inputMsgs, message, rule, bot)
+
+	defer c.Shutdown(context.Background()) //nolint:errcheck
+
+	if c.interactionsServer == nil {
+		t.Fatal("expected InteractiveComponents to start a server")
+	}
+
+	if c.interactionsServer.Addr != bot.SlackInteractionsListenAddr {
+		t.Fatalf("expected server addr %q, got %q", bot.SlackInteractionsListenAddr, c.interactionsServer.Addr)
+	}
+
+	if !hasRoute(router, bot.SlackInteractionsCallbackPath) {
+		t.Fatal("expected InteractiveComponents to register its rule route on the shared Router")
+	}
+
+	if !hasRoute(router, "/interaction_health") {
+		t.Fatal("expected InteractiveComponents to register its health route on the shared Router")
+	}
+
+	first := c.interactionsServer
+
+	// A second hit rule should reuse the already-running server rather
+	// than starting a new one.
+	c.InteractiveComponents(inputMsgs, message, rule, bot)
+
+	if c.interactionsServer != first {
+		t.Fatal("expected a second InteractiveComponents call to reuse the existing server")
+	}
+}
+
+func TestClient_InteractiveComponents_TLS(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	bot := &model.Bot{
+		Log:                           logger,
+		InteractiveComponents:         true,
+		SlackInteractionsCallbackPath: "/slack/interactions",
+		SlackInteractionsListenAddr:   "127.0.0.1:0",
+		SlackInteractionsTLSCert:      "testdata/does-not-exist.crt",
+		SlackInteractionsTLSKey:       "testdata/does-not-exist.key",
+	}
+	c := &Client{VerificationToken: "test-verification-token"}
+	inputMsgs := make(chan model.Message, 1)
+	rule := model.Rule{Name: "test-rule"}
+	message := &model.Message{}
+
+	c.InteractiveComponents(inputMsgs, message, rule, bot)
+
+	defer c.Shutdown(context.Background()) //nolint:errcheck
+
+	// InteractiveComponents always logs an Info entry when it starts the
+	// server, so only an Error entry shows the TLS failure itself.
+	hasError := func() bool {
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.ErrorLevel {
+				return true
+			}
+		}
+		return false
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if hasError() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !hasError() {
+		t.Fatal("expected a missing TLS cert/key to produce a logged server error")
+	}
+}
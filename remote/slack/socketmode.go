@@ -0,0 +1,63 @@
+package slack
+
+import (
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/target/flottbot/model"
+)
+
+// readFromSocketMode connects to Slack over Socket Mode using the
+// app-level token already attached to api (see Client.new) and funnels
+// incoming events and interaction payloads into the same channels the
+// RTM/Events API readers use. Unlike the Events API reader, no public
+// HTTP endpoint is required - everything arrives over a single outbound
+// websocket, so the interactions server started by InteractiveComponents
+// is never needed in this mode.
+func readFromSocketMode(api *slack.Client, inputMsgs chan<- model.Message, bot *model.Bot) {
+	smClient := socketmode.New(api)
+
+	go func() {
+		for evt := range smClient.Events {
+			switch evt.Type {
+			case socketmode.EventTypeEventsAPI:
+				eventsAPIEvent, ok := evt.Data.(slackevents.EventsAPIEvent)
+				if !ok {
+					bot.Log.Debug("Ignored unexpected EventsAPI payload in Socket Mode")
+					continue
+				}
+
+				smClient.Ack(*evt.Request)
+
+				// Reuse the same inner-event parsing the Events API
+				// callback handler uses to build a model.Message.
+				parseEventsAPIInnerEvent(eventsAPIEvent, inputMsgs, bot)
+			case socketmode.EventTypeInteractive:
+				callback, ok := evt.Data.(slack.InteractionCallback)
+				if !ok {
+					bot.Log.Debug("Ignored unexpected interaction payload in Socket Mode")
+					continue
+				}
+
+				smClient.Ack(*evt.Request)
+
+				// A view_submission/view_closed for a modal opened by
+				// openModal is handled the same way the HTTP interactions
+				// endpoint handles it; anything else falls through to the
+				// normal interaction processing so rule matching behaves
+				// identically regardless of transport.
+				if dispatchViewCallback(callback, inputMsgs, bot) {
+					continue
+				}
+
+				handleInteractionCallback(callback, inputMsgs, bot)
+			}
+		}
+	}()
+
+	bot.Log.Info("Slack Socket Mode reader is connected")
+
+	if err := smClient.Run(); err != nil {
+		bot.Log.Errorf("Slack Socket Mode reader exited: %s", err)
+	}
+}